@@ -5,12 +5,8 @@
 package bellt
 
 import (
-	"bytes"
-	"context"
-	"errors"
 	"fmt"
 	"net/http"
-	"regexp"
 	"strings"
 )
 
@@ -23,14 +19,35 @@ var (
 		"PUT",
 		"DELETE",
 	}
-	mainRouter *Router
+
+	// mountMethods lists every standard HTTP method Mount forwards to the
+	// mounted handler. A pass-through mount isn't in the business of
+	// validating method names the way a user-declared HandleFunc route is
+	// - that's the mounted handler's problem - so it registers beyond the
+	// checkMethods-recognized subset above, including HEAD, OPTIONS and
+	// PATCH.
+	mountMethods = []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+		http.MethodConnect,
+		http.MethodOptions,
+		http.MethodTrace,
+	}
 )
 
-// Router is a struct responsible for storing routes already available (Route)
-// or routes that will still be available (BuiltRoute).
+// Router is a struct responsible for storing and matching every route
+// registered through HandleFunc. Each HTTP method owns its own radix tree
+// so that request dispatch never has to scan routes registered under a
+// different method.
 type Router struct {
-	routes []*Route
-	built  []*BuiltRoute
+	trees      map[string]*node // default, host-less routes
+	hostExact  map[string]*hostBucket
+	hostWild   []*hostBucket // ordered, tried in registration order
+	middleware []Middleware
 }
 
 // Route is a struct responsible for storing basic information of a Route, with
@@ -38,7 +55,7 @@ type Router struct {
 type Route struct {
 	Path    string
 	Handler http.HandlerFunc
-	Params  []Variable
+	Methods []string
 }
 
 // SubHandle is a struct similar to Route, however its behavior must be related
@@ -49,97 +66,94 @@ type SubHandle struct {
 	Methods []string
 }
 
-// BuiltRoute is an internal pattern struct for routes that will be built at
-// run time.
-type BuiltRoute struct {
-	TempPath string
-	Handler  http.HandlerFunc
-	Var      map[int]Variable
-	KeyRoute string
-	Methods  []string
-}
-
-// Variable is a struct that guarantees the correct mapping of variables used
-// in built routes.
-type Variable struct {
-	Name  string
-	Value string
-}
-
-// ParamReceiver is responsible to return params set on context
-type ParamReceiver struct {
-	request *http.Request
-}
-
 // Middleware is a type responsible for characterizing middleware functions
 // that should be used in conjunction with bellt.Use().
 type Middleware func(http.HandlerFunc) http.HandlerFunc
 
-// Key is a type responsible for define a requester key param
-type key string
-
-// NewRouter is responsible to initialize a "singleton" router instance.
+// NewRouter is responsible to initialize a new, independent Router
+// instance. It registers no routes on http.DefaultServeMux: pass the
+// returned Router directly to http.ListenAndServe, or mux.Handle, or Mount
+// it under another Router.
 func NewRouter() *Router {
-	if mainRouter == nil {
-		http.HandleFunc("/health", healthApplication)
-		http.HandleFunc("/", redirectBuiltRoute)
-		mainRouter = &Router{}
+	r := &Router{
+		trees:     make(map[string]*node),
+		hostExact: make(map[string]*hostBucket),
 	}
-	return mainRouter
+	r.HandleFunc("/health", healthApplication, "GET")
+	return r
 }
 
 /*
-	Router is a struct responsible for storing routes already available (Route)
-	or routes that will still be available (BuiltRoute).
-
-	Its initialization is done through the method NewRouter:
+	Router is a struct responsible for storing and matching every route
+	registered through HandleFunc. Since Router implements http.Handler, it
+	can be served directly:
 
 		router: = bellt.NewRouter ()
 
 		func main () {
 			[...]
-			log.Fatal (http.ListenAndServe (": 8080", nil))
+			log.Fatal (http.ListenAndServe (": 8080", router))
 		}
 */
 
-// Method to obtain router for interanl processing
-func getRouter() *Router {
-	return mainRouter
-}
+// ServeHTTP implements http.Handler. It first tries the host bucket whose
+// pattern matches req.Host - an O(1) lookup for an exact host, falling back
+// to the small ordered list of wildcard/param host patterns - and only
+// then falls back to routes registered with no Host constraint at all.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	host := stripPort(req.Host)
+
+	if b, ok := r.hostExact[strings.ToLower(host)]; ok {
+		if ep, params := lookupTree(b.trees, req.Method, req.URL.Path); ep != nil {
+			dispatch(w, req, ep, params)
+			return
+		}
+	}
 
-// RedirectBuiltRoute Performs code analysis assigning values to variables
-// in execution time.
-func redirectBuiltRoute(w http.ResponseWriter, r *http.Request) {
-	selectedBuilt, params := getRequestParams(r.URL.Path)
-
-	if selectedBuilt != nil {
-		router := getRouter()
-		for idx, varParam := range selectedBuilt.Var {
-			selectedBuilt.Var[idx] = Variable{
-				Name:  varParam.Name,
-				Value: params[idx],
-			}
+	for _, b := range r.hostWild {
+		hostParams, ok := b.pattern.match(host)
+		if !ok {
+			continue
 		}
-		var allParams []Variable
-		for _, param := range selectedBuilt.Var {
-			allParams = append(allParams, param)
+		if ep, params := lookupTree(b.trees, req.Method, req.URL.Path); ep != nil {
+			dispatch(w, req, ep, append(hostParams, params...))
+			return
 		}
-		router.createBuiltRoute(
-			selectedBuilt.TempPath,
-			selectedBuilt.Handler,
-			selectedBuilt.Methods,
-			selectedBuilt.Var)
-
-		setRouteParams(gateMethod(
-			selectedBuilt.Handler,
-			selectedBuilt.Methods...),
-			allParams).ServeHTTP(w, r)
-	} else {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte(`{"msg": "route not found"}`))
 	}
 
+	if ep, params := lookupTree(r.trees, req.Method, req.URL.Path); ep != nil {
+		dispatch(w, req, ep, params)
+		return
+	}
+
+	writeNotFound(w)
+}
+
+func lookupTree(trees map[string]*node, method, path string) (*endpoint, []param) {
+	tree, ok := trees[method]
+	if !ok {
+		return nil, nil
+	}
+	return tree.lookup(path, nil)
+}
+
+func dispatch(w http.ResponseWriter, req *http.Request, ep *endpoint, params []param) {
+	req = req.WithContext(withRouteParams(req.Context(), params))
+	ep.handler(w, req)
+}
+
+// RegisterDefault mounts r onto http.DefaultServeMux at "/", mirroring how
+// NewRouter used to wire a router in automatically before Router became an
+// http.Handler in its own right. New code should prefer
+// http.ListenAndServe(addr, r) or mux.Handle(prefix, r) instead.
+func (r *Router) RegisterDefault() {
+	http.Handle("/", r)
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte(`{"msg": "route not found"}`))
 }
 
 // Use becomes responsible for executing all middlewares passed through a
@@ -175,45 +189,65 @@ func Use(handler http.HandlerFunc, middleware ...Middleware) http.HandlerFunc {
 // Router methods
 // ----------------------------------------------------------------------------
 
-// HandleFunc function responsible for initializing a common route or built
-// through the Router. All non-grouped routes must be initialized by this
-// method.
+// HandleFunc function responsible for initializing a route through the
+// Router. All routes, static or parameterized, must be initialized by this
+// method. A genuine route conflict (two patterns that would be ambiguous to
+// match) panics at registration time; an invalid method name in methods
+// does not - it simply leaves that route unregistered, as it always has,
+// rather than crash a running process over a typo.
 func (r *Router) HandleFunc(path string, handleFunc http.HandlerFunc, methods ...string) {
-	key, values := getBuiltRouteParams(path)
-	if values != nil {
-		valuesList := make(map[int]Variable)
-
-		for idx, name := range values {
-			valuesList[idx] = Variable{
-				Name:  name[1],
-				Value: "",
-			}
-		}
-
-		builtRoute := &BuiltRoute{
-			TempPath: path,
-			Handler:  handleFunc,
-			Var:      valuesList,
-			KeyRoute: key,
-			Methods:  methods,
-		}
+	if err := checkMethods(path, methods...); err != nil {
+		registrationPanic(err)
+		return
+	}
+	registrationPanic(r.handle("", path, Use(handleFunc, r.middleware...), methods...))
+}
 
-		r.built = append(r.built, builtRoute)
+// registrationPanic panics on every registration error except a methodErr,
+// which HandleFunc and Group.HandleFunc both treat as non-fatal.
+func registrationPanic(err error) {
+	if err == nil {
+		return
+	}
+	if _, ok := err.(*methodErr); ok {
+		return
+	}
+	panic(err)
+}
 
-	} else {
+// handle registers handleFunc under the trees owned by host's bucket (the
+// Router's own host-less trees when host is empty). Unlike HandleFunc and
+// Group.HandleFunc, it does not validate methods against checkMethods -
+// callers that need that guard (the two above) run it themselves first;
+// Mount deliberately skips it to register every HTTP method, not just the
+// ones HandleFunc recognizes for user-declared routes.
+func (r *Router) handle(host, path string, handleFunc http.HandlerFunc, methods ...string) error {
+	segs, err := parsePattern(path)
+	if err != nil {
+		return err
+	}
 
-		route := &Route{
-			Path:    path,
-			Handler: handleFunc,
+	trees := r.trees
+	if host != "" {
+		b, err := r.bucketForHost(host)
+		if err != nil {
+			return err
 		}
-		err := route.methods(methods...)
+		trees = b.trees
+	}
 
-		if err == nil {
-			r.routes = append(r.routes, route)
+	for _, method := range methods {
+		tree, ok := trees[method]
+		if !ok {
+			tree = newNode(nodeStatic)
+			trees[method] = tree
+		}
+		if err := tree.insert(segs, method, path, handleFunc); err != nil {
+			return err
 		}
-
 	}
 
+	return nil
 }
 
 /*
@@ -233,13 +267,53 @@ func (r *Router) HandleFunc(path string, handleFunc http.HandlerFunc, methods ..
 		}
 */
 
-// HandleGroup used to create and define a group of sub-routes
+// Host returns a Group whose routes only match requests whose Host header
+// satisfies pattern. pattern may be a literal ("api.example.com"), contain
+// a single-label wildcard ("*.example.com"), or capture labels with
+// "{name}" / "{name:constraint}" ("{tenant}.example.com"), in which case
+// the captured value is accessible the same way as a path param, through
+// RouteVariables(r).GetVar("tenant").
+func (r *Router) Host(pattern string) *Group {
+	return &Group{router: r, host: pattern}
+}
+
+// Mount attaches h under prefix, forwarding every method and every path
+// below prefix to it with prefix stripped from r.URL.Path, following the
+// chi/gin convention for composing handlers (including other *Router
+// instances) into a single tree. The bare prefix itself (no trailing
+// segment, e.g. "/api" for a "/api" mount) is also forwarded to h at "/",
+// matching chi's Mount.
+func (r *Router) Mount(prefix string, h http.Handler) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	const mountVar = "bellt_mounted"
+
+	forward := func(w http.ResponseWriter, req *http.Request, path string) {
+		sub := new(http.Request)
+		*sub = *req
+		u := *req.URL
+		u.Path = path
+		sub.URL = &u
+		h.ServeHTTP(w, sub)
+	}
+
+	rootHandler := Use(func(w http.ResponseWriter, req *http.Request) {
+		forward(w, req, "/")
+	}, r.middleware...)
+	subHandler := Use(func(w http.ResponseWriter, req *http.Request) {
+		forward(w, req, "/"+fmt.Sprint(RouteVariables(req).GetVar(mountVar)))
+	}, r.middleware...)
+
+	registrationPanic(r.handle("", prefix, rootHandler, mountMethods...))
+	registrationPanic(r.handle("", prefix+"/*"+mountVar, subHandler, mountMethods...))
+}
+
+// HandleGroup used to create and define a group of sub-routes. It is a
+// thin wrapper over Group: mainPath becomes the Group's prefix and each
+// SubHandle is registered through Group.HandleFunc.
 func (r *Router) HandleGroup(mainPath string, sr ...*SubHandle) {
+	group := r.Group(mainPath)
 	for _, route := range sr {
-		var buf bytes.Buffer
-		buf.WriteString(mainPath)
-		buf.WriteString(route.Path)
-		r.HandleFunc(buf.String(), route.Handler, route.Methods...)
+		group.HandleFunc(route.Path, route.Handler, route.Methods...)
 	}
 }
 
@@ -257,60 +331,19 @@ func (r *Router) SubHandleFunc(path string, handleFunc http.HandlerFunc,
 	return handleDetail
 }
 
-// Internal method of route construction based on parameters passed in the
-// HandleFunc, guaranteeing a valid and functional route.
-func (r *Router) routeBuilder(path string, handleFunc http.HandlerFunc,
-	params ...Variable) *Route {
-	route := &Route{
-		Handler: handleFunc,
-		Path:    path,
-		Params:  params,
-	}
-
-	r.routes = append(r.routes, route)
-	return route
-}
-
-// Internal method responsible for standardizing built routes in order to
-// generate valid models of used.
-func (r *Router) createBuiltRoute(path string, handler http.HandlerFunc,
-	methods []string, params map[int]Variable) {
-	var (
-		builtPath = path
-		allParams []Variable
-	)
-
-	for _, param := range params {
-		builtPath = strings.Replace(builtPath, "{"+param.Name+"}",
-			param.Value, -1)
-		allParams = append(allParams, param)
-	}
-
-	r.routeBuilder(builtPath, handler, allParams...).methods(methods...)
-}
+// methodErr reports that HandleFunc was called with an HTTP method bellt
+// doesn't recognize. It is its own type, rather than a plain errors.New, so
+// registrationPanic can tell it apart from a genuine route conflict.
+type methodErr struct{ msg string }
 
-// ----------------------------------------------------------------------------
-// Route methods
-// ----------------------------------------------------------------------------
+func (e *methodErr) Error() string { return e.msg }
 
-// Internal method responsible for validating if the request method used exists
-// for the route presented.
-func (r *Route) methods(methods ...string) (err error) {
+// Internal method responsible for validating if the request methods used
+// exist, returning an error naming the offending path/method otherwise.
+func checkMethods(path string, methods ...string) (err error) {
 	for _, method := range methods {
 		if !checkMethod(method) {
-			msgErro := fmt.Sprintf("Method %s on %s not allowed",
-				method, r.Path)
-			err = errors.New(msgErro)
-		}
-	}
-	if err == nil {
-		if len(r.Params) > 0 {
-			http.HandleFunc(r.Path,
-				setRouteParams(gateMethod(r.Handler, methods...), r.Params))
-		} else {
-			http.HandleFunc(r.Path, gateMethod(r.Handler,
-				methods...))
-
+			err = &methodErr{fmt.Sprintf("Method %s on %s not allowed", method, path)}
 		}
 	}
 	return
@@ -327,95 +360,6 @@ func checkMethod(m string) bool {
 	return false
 }
 
-// ----------------------------------------------------------------------------
-// Router middlewares
-// ----------------------------------------------------------------------------
-
-// Ensures that routing is done using valid methods
-func gateMethod(next http.HandlerFunc, methods ...string) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		for _, method := range methods {
-			if r.Method == method {
-				next.ServeHTTP(w, r)
-				return
-			}
-		}
-
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte(`{"error": "The method for this route doesnt exist"}`))
-
-	}
-}
-
-//	Method to obtain route params in a built route
-func getBuiltRouteParams(path string) (string, [][]string) {
-	rgx := regexp.MustCompile(`(?m){(\w*)}`)
-	rgxStart := regexp.MustCompile(`(?m)(^\/)`)
-	rgxEnd := regexp.MustCompile(`(?m)(\/$)`)
-	return rgxEnd.ReplaceAllString(rgxStart.ReplaceAllString(
-		rgx.Split(path, -1)[0], ""), ""), rgx.FindAllStringSubmatch(path, -1)
-}
-
-// Method to obtain request methods
-func getRequestParams(path string) (*BuiltRoute, map[int]string) {
-	router := getRouter()
-
-	var builtRouteList *BuiltRoute
-	params := make(map[int]string)
-
-	for _, route := range router.built {
-		rgx := regexp.MustCompile(route.KeyRoute)
-		if rgx.FindString(path) != "" {
-			if (len(strings.Split(
-				rgx.Split(path, -1)[1], "/")) - 1) == len(route.Var) {
-				builtRouteList = route
-				for idx, val := range strings.Split(rgx.Split(path, -1)[1],
-					"/") {
-					if idx != 0 {
-						params[idx-1] = val
-					}
-				}
-			}
-		}
-	}
-	return builtRouteList, params
-}
-
-// RouteVariables used to capture and store parameters passed to built routes
-func RouteVariables(r *http.Request) *ParamReceiver {
-
-	receiver := ParamReceiver{
-		request: r,
-	}
-
-	return &receiver
-}
-
-// Defines and organizes route parameters by applying them in request
-func setRouteParams(next http.HandlerFunc, params []Variable) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-
-		for _, param := range params {
-			name := key(param.Name)
-			ctx = context.WithValue(ctx, name, param.Value)
-		}
-
-		r = r.WithContext(ctx)
-
-		next.ServeHTTP(w, r)
-	}
-}
-
-// ----------------------------------------------------------------------------
-// ParamReceiver middlewares
-// ----------------------------------------------------------------------------
-
-// GetVar return a value of router variable
-func (pr *ParamReceiver) GetVar(variable string) interface{} {
-	return pr.request.Context().Value(key(variable))
-}
-
 // ----------------------------------------------------------------------------
 // Server support methods
 // ----------------------------------------------------------------------------
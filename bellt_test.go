@@ -0,0 +1,113 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouterIsAnHTTPHandler(t *testing.T) {
+	router := NewRouter()
+	router.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}, "GET")
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/ping")
+	if err != nil {
+		t.Fatalf("GET /ping: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestRouterMount(t *testing.T) {
+	router := NewRouter()
+
+	sub := NewRouter()
+	sub.HandleFunc("/widgets", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("widgets"))
+	}, "GET")
+
+	sub.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("root"))
+	}, "GET")
+
+	router.Mount("/api", sub)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/widgets", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "widgets" {
+		t.Fatalf("expected the mounted sub-router to handle /api/widgets, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/api", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "root" {
+		t.Fatalf("expected a bare request to the mount prefix to reach the sub-router's \"/\", got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "root" {
+		t.Fatalf("expected a trailing-slash request to the mount prefix to reach the sub-router's \"/\", got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRouterMountForwardsEveryMethod(t *testing.T) {
+	router := NewRouter()
+
+	var gotMethod string
+	router.Mount("/proxy", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, method := range []string{"HEAD", "OPTIONS", "PATCH"} {
+		gotMethod = ""
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, "/proxy/widgets", nil))
+		if rec.Code != http.StatusOK || gotMethod != method {
+			t.Fatalf("expected Mount to forward %s, got %d (method seen: %q)", method, rec.Code, gotMethod)
+		}
+	}
+}
+
+func TestHandleFuncPanicsOnRouteConflict(t *testing.T) {
+	router := NewRouter()
+	router.HandleFunc("/users/{id}", func(w http.ResponseWriter, r *http.Request) {}, "GET")
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected HandleFunc to panic on a conflicting route")
+		}
+	}()
+	router.HandleFunc("/users/{name}", func(w http.ResponseWriter, r *http.Request) {}, "GET")
+}
+
+func TestHandleFuncDoesNotPanicOnBadMethod(t *testing.T) {
+	router := NewRouter()
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("expected an invalid method to be a no-op, not panic: %v", r)
+		}
+	}()
+	router.HandleFunc("/widgets", func(w http.ResponseWriter, r *http.Request) {}, "PATCH")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("PATCH", "/widgets", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected the unregistered route to 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BindErrorResponder writes the response for a request that failed to
+// decode or validate against a Bind[T] struct. It can be reassigned to
+// customize the error format; the default replies 400 with
+// {"errors": [...]}.
+var BindErrorResponder = func(w http.ResponseWriter, r *http.Request, errs []string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	body, _ := json.Marshal(map[string][]string{"errors": errs})
+	w.Write(body)
+}
+
+// bindKey scopes a bound value in the request context by its concrete
+// type, so that stacking more than one Bind[T] middleware on a route (one
+// per T) never collides.
+type bindKey struct{ t reflect.Type }
+
+// Bind returns a Middleware that, for every request, allocates a fresh *T,
+// populates it from the request (a JSON body for application/json, form
+// values for application/x-www-form-urlencoded and multipart/form-data,
+// and path/query params via the `bellt:"path,name"` / `bellt:"query,name"`
+// struct tags), validates it against any `validate` tags present, and
+// stashes the result on the request's context for Form[T] to retrieve. On
+// decode or validation failure it short-circuits the chain through
+// BindErrorResponder instead of calling next.
+//
+// Bind does not depend on go-playground/validator; its `validate` tag
+// vocabulary is intentionally a small, fixed subset: "required", "min=N"
+// and "max=N" (string length for strings, numeric bounds for numbers). Any
+// other rule - "email", "oneof=...", "dive", and the rest of that
+// package's surface - is a no-op: a struct carrying one of those tags
+// still binds and still enforces the rules Bind does understand, rather
+// than refusing to boot or failing every request over a tag it can't
+// check.
+func Bind[T any](_ T) Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var v T
+
+			if err := decodeBody(&v, r); err != nil {
+				BindErrorResponder(w, r, []string{err.Error()})
+				return
+			}
+			bindPathAndQuery(&v, r)
+
+			if errs := validateStruct(&v); len(errs) > 0 {
+				BindErrorResponder(w, r, errs)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), bindKey{reflect.TypeOf(v)}, &v)
+			next(w, r.WithContext(ctx))
+		}
+	}
+}
+
+// Form retrieves the value bound by Bind[T] for this request, or nil if
+// no Bind[T] middleware ran (or ran for a different type T).
+func Form[T any](r *http.Request) *T {
+	var zero T
+	v, _ := r.Context().Value(bindKey{reflect.TypeOf(zero)}).(*T)
+	return v
+}
+
+// decodeBody populates v from the request body according to its
+// Content-Type; requests with no recognized body (GET, DELETE, ...) are
+// left untouched so path/query binding can still run.
+func decodeBody(v interface{}, r *http.Request) error {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return fmt.Errorf("bellt: invalid Content-Type: %v", err)
+	}
+
+	switch mediaType {
+	case "application/json":
+		defer r.Body.Close()
+		if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+			return fmt.Errorf("bellt: invalid JSON body: %v", err)
+		}
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return fmt.Errorf("bellt: invalid form body: %v", err)
+		}
+		bindValues(v, r.Form, "form")
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return fmt.Errorf("bellt: invalid multipart body: %v", err)
+		}
+		bindValues(v, r.Form, "form")
+	}
+
+	return nil
+}
+
+// bindPathAndQuery fills in every field tagged `bellt:"path,name"` or
+// `bellt:"query,name"` from the matched route params and the request's
+// query string, respectively.
+func bindPathAndQuery(v interface{}, r *http.Request) {
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		kind, name, ok := parseBindTag(field.Tag.Get("bellt"))
+		if !ok {
+			continue
+		}
+
+		var raw string
+		switch kind {
+		case "path":
+			value := RouteVariables(r).GetVar(name)
+			if value == nil {
+				continue
+			}
+			raw = fmt.Sprint(value)
+		case "query":
+			if !r.URL.Query().Has(name) {
+				continue
+			}
+			raw = r.URL.Query().Get(name)
+		default:
+			continue
+		}
+
+		setField(rv.Field(i), raw)
+	}
+}
+
+// bindValues fills every field tagged `bellt:"<kind>,name"` (or, absent a
+// tag, matched by the lowercased field name) from values.
+func bindValues(v interface{}, values map[string][]string, defaultKind string) {
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		kind, name, ok := parseBindTag(field.Tag.Get("bellt"))
+		if !ok {
+			kind, name = defaultKind, strings.ToLower(field.Name)
+		}
+		if kind != defaultKind {
+			continue
+		}
+
+		raw, ok := values[name]
+		if !ok || len(raw) == 0 {
+			continue
+		}
+		setField(rv.Field(i), raw[0])
+	}
+}
+
+// parseBindTag parses a `bellt:"kind,name"` struct tag.
+func parseBindTag(tag string) (kind, name string, ok bool) {
+	if tag == "" {
+		return "", "", false
+	}
+	parts := strings.SplitN(tag, ",", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// setField coerces raw into fv's underlying kind, ignoring values that
+// cannot be converted so an empty or malformed param simply leaves the
+// field at its zero value.
+func setField(fv reflect.Value, raw string) {
+	if !fv.CanSet() {
+		return
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			fv.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			fv.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, err := strconv.ParseFloat(raw, 64); err == nil {
+			fv.SetFloat(n)
+		}
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(raw); err == nil {
+			fv.SetBool(b)
+		}
+	}
+}
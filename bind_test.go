@@ -0,0 +1,96 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type createUserReq struct {
+	ID   string `bellt:"path,id"`
+	Name string `json:"name" validate:"required"`
+	Age  int    `json:"age" validate:"min=0,max=150"`
+}
+
+func TestBindAndFormRoundTripJSON(t *testing.T) {
+	r := &Router{trees: make(map[string]*node), hostExact: make(map[string]*hostBucket)}
+
+	var got *createUserReq
+	r.HandleFunc("/users/{id}", Use(func(w http.ResponseWriter, req *http.Request) {
+		got = Form[createUserReq](req)
+		w.WriteHeader(http.StatusOK)
+	}, Bind(createUserReq{})), "POST")
+
+	body := strings.NewReader(`{"name":"Ada","age":30}`)
+	req := httptest.NewRequest("POST", "/users/42", body)
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got == nil || got.ID != "42" || got.Name != "Ada" || got.Age != 30 {
+		t.Fatalf("unexpected bound value: %+v", got)
+	}
+}
+
+func TestBindRejectsFailedValidation(t *testing.T) {
+	r := &Router{trees: make(map[string]*node), hostExact: make(map[string]*hostBucket)}
+	r.HandleFunc("/users/{id}", Use(func(w http.ResponseWriter, req *http.Request) {
+		t.Fatal("handler should not run when validation fails")
+	}, Bind(createUserReq{})), "POST")
+
+	body := strings.NewReader(`{"name":"","age":30}`)
+	req := httptest.NewRequest("POST", "/users/42", body)
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a missing required field, got %d", rec.Code)
+	}
+}
+
+func TestBindIgnoresUnsupportedValidateRuleButStillEnforcesKnownOnes(t *testing.T) {
+	type reqWithEmail struct {
+		Email string `validate:"required,email"`
+	}
+
+	r := &Router{trees: make(map[string]*node), hostExact: make(map[string]*hostBucket)}
+	var ran bool
+	r.HandleFunc("/signup", Use(func(w http.ResponseWriter, req *http.Request) {
+		ran = true
+		w.WriteHeader(http.StatusOK)
+	}, Bind(reqWithEmail{})), "POST")
+
+	body := strings.NewReader(`{"Email":"not-an-email-but-required-is-satisfied"}`)
+	req := httptest.NewRequest("POST", "/signup", body)
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if !ran || rec.Code != http.StatusOK {
+		t.Fatalf("expected the unrecognized \"email\" rule to be a no-op, got %d (handler ran: %v)", rec.Code, ran)
+	}
+
+	body = strings.NewReader(`{"Email":""}`)
+	req = httptest.NewRequest("POST", "/signup", body)
+	req.Header.Set("Content-Type", "application/json")
+
+	rec = httptest.NewRecorder()
+	ran = false
+	r.ServeHTTP(rec, req)
+
+	if ran || rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected \"required\" to still be enforced alongside the ignored \"email\" rule, got %d (handler ran: %v)", rec.Code, ran)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UUID is bellt's own representation of a parsed UUID value, returned by
+// the {name:uuid} param constraint and ParamReceiver.GetUUID. It is
+// deliberately NOT github.com/google/uuid.UUID or any other third-party
+// UUID type: bellt has no dependencies today, and taking one on solely to
+// type a path param is a bigger call than this constraint warrants. UUID
+// only validates the canonical 8-4-4-4-12 hex-with-dashes shape (see
+// uuidPattern) and lowercases it; it does not parse UUID versions/variants
+// or support the non-hyphenated/URN forms a full UUID library would.
+// Callers that need the real type can convert with uuid.Parse(string(v)).
+type UUID string
+
+var uuidPattern = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// constraint is attached to a param tree node and decides whether a path
+// segment is allowed to bind to it, optionally coercing the raw string into
+// a typed value stored on the matched param.
+type constraint struct {
+	spec    string // original text after the ':' in "{name:spec}"
+	re      *regexp.Regexp
+	convert func(string) (interface{}, error)
+}
+
+// match reports whether seg satisfies the constraint and, when the
+// constraint coerces its value, returns the typed result.
+func (c *constraint) match(seg string) (interface{}, bool) {
+	if c.re != nil && !c.re.MatchString(seg) {
+		return nil, false
+	}
+	if c.convert == nil {
+		return nil, true
+	}
+	v, err := c.convert(seg)
+	if err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// isTimeLayout reports whether spec looks like a Go reference time layout
+// (e.g. "2006-01-02") rather than a regular expression.
+func isTimeLayout(spec string) bool {
+	return strings.Contains(spec, "2006")
+}
+
+// parseConstraint compiles the text following ':' in a "{name:spec}" param
+// into a constraint, recognizing the "int" and "uuid" shortcuts, Go
+// reference time layouts, and otherwise treating spec as a regular
+// expression that must match the whole segment.
+func parseConstraint(spec string) (*constraint, error) {
+	switch spec {
+	case "int":
+		return &constraint{
+			spec: spec,
+			re:   regexp.MustCompile(`^-?[0-9]+$`),
+			convert: func(s string) (interface{}, error) {
+				return strconv.Atoi(s)
+			},
+		}, nil
+	case "uuid":
+		return &constraint{
+			spec: spec,
+			re:   uuidPattern,
+			convert: func(s string) (interface{}, error) {
+				return UUID(strings.ToLower(s)), nil
+			},
+		}, nil
+	}
+
+	if isTimeLayout(spec) {
+		layout := spec
+		return &constraint{
+			spec: spec,
+			convert: func(s string) (interface{}, error) {
+				return time.Parse(layout, s)
+			},
+		}, nil
+	}
+
+	re, err := regexp.Compile("^(?:" + spec + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("bellt: invalid param constraint %q: %v", spec, err)
+	}
+	return &constraint{spec: spec, re: re}, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConstraintOverlappingIntAndNamePattern(t *testing.T) {
+	r := &Router{trees: make(map[string]*node), hostExact: make(map[string]*hostBucket)}
+
+	var got string
+	r.HandleFunc("/users/{id:int}", func(w http.ResponseWriter, req *http.Request) {
+		got = "int"
+	}, "GET")
+	r.HandleFunc("/users/{name:[a-z]+}", func(w http.ResponseWriter, req *http.Request) {
+		got = "name"
+	}, "GET")
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/42", nil))
+	if got != "int" {
+		t.Fatalf("expected the {id:int} route to match /users/42, got %q", got)
+	}
+
+	got = ""
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/bob", nil))
+	if got != "name" {
+		t.Fatalf("expected the {name:[a-z]+} route to match /users/bob, got %q", got)
+	}
+}
+
+func TestConstraintCoercesTypedValue(t *testing.T) {
+	r := &Router{trees: make(map[string]*node), hostExact: make(map[string]*hostBucket)}
+
+	var id int
+	var uuid UUID
+	r.HandleFunc("/users/{id:int}", func(w http.ResponseWriter, req *http.Request) {
+		id = RouteVariables(req).GetInt("id")
+	}, "GET")
+	r.HandleFunc("/teams/{tid:uuid}", func(w http.ResponseWriter, req *http.Request) {
+		uuid = RouteVariables(req).GetUUID("tid")
+	}, "GET")
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/42", nil))
+	if id != 42 {
+		t.Fatalf("expected GetInt to return 42, got %d", id)
+	}
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(
+		"GET", "/teams/550E8400-E29B-41D4-A716-446655440000", nil))
+	if uuid != "550e8400-e29b-41d4-a716-446655440000" {
+		t.Fatalf("expected a lowercased UUID, got %q", uuid)
+	}
+}
+
+func TestConstraintRejectsNonMatchingSegment(t *testing.T) {
+	r := &Router{trees: make(map[string]*node), hostExact: make(map[string]*hostBucket)}
+	r.HandleFunc("/users/{id:int}", func(w http.ResponseWriter, req *http.Request) {}, "GET")
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/users/not-an-int", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a segment that fails its constraint, got %d", rec.Code)
+	}
+}
+
+func TestParseConstraintInvalidRegexp(t *testing.T) {
+	if _, err := parseConstraint("[a-"); err == nil {
+		t.Fatal("expected an error for an invalid regular expression constraint")
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import "net/http"
+
+// Mux is implemented by both Router and Group so that top-level routes and
+// prefixed, middleware-wrapped subtrees can be registered through the same
+// API.
+type Mux interface {
+	HandleFunc(path string, handleFunc http.HandlerFunc, methods ...string)
+	Use(mw ...Middleware)
+	Group(prefix string) *Group
+	With(mw ...Middleware) *Group
+	Host(pattern string) *Group
+}
+
+// Group is a prefixed subtree of a Router that carries its own ordered
+// middleware stack and, optionally, a Host constraint. parent is nil for a
+// Group rooted directly on a Router; a nested Group instead resolves its
+// prefix, Host and middleware through parent at registration time, so a Use
+// call on an ancestor - whether made before or after the Group was created -
+// is always picked up, as long as it runs before HandleFunc does.
+type Group struct {
+	router     *Router
+	parent     *Group
+	prefix     string
+	host       string
+	middleware []Middleware
+}
+
+// Group creates a top-level Group rooted at prefix. Its middleware stack is
+// resolved from the Router at registration time, so routes registered
+// through it inherit whatever Use has accumulated on the Router by then.
+func (r *Router) Group(prefix string) *Group {
+	return &Group{router: r, prefix: prefix}
+}
+
+// With returns a new Group sharing the Router's prefix (none) plus the
+// extra middleware, without mutating the Router's own stack.
+func (r *Router) With(mw ...Middleware) *Group {
+	return r.Group("").With(mw...)
+}
+
+// Use appends middleware to the Router's own stack. Every route and Group
+// registered afterwards inherits it.
+func (r *Router) Use(mw ...Middleware) {
+	r.middleware = append(r.middleware, mw...)
+}
+
+// Group creates a nested Group whose prefix is appended to the parent's.
+// Its middleware stack is resolved through g at registration time rather
+// than copied up front.
+func (g *Group) Group(prefix string) *Group {
+	return &Group{router: g.router, parent: g, prefix: prefix}
+}
+
+// With returns a new Group, scoped the same as g, with the extra middleware
+// appended after g's own stack. Unlike Use it does not mutate g.
+func (g *Group) With(mw ...Middleware) *Group {
+	return &Group{
+		router:     g.router,
+		parent:     g,
+		middleware: append([]Middleware{}, mw...),
+	}
+}
+
+// Host returns a Group scoped the same as g, but constrained to requests
+// whose Host header satisfies pattern, replacing any Host constraint g
+// already carried.
+func (g *Group) Host(pattern string) *Group {
+	return &Group{
+		router:     g.router,
+		parent:     g.parent,
+		prefix:     g.prefix,
+		host:       pattern,
+		middleware: append([]Middleware{}, g.middleware...),
+	}
+}
+
+// Use appends middleware to the Group's own stack. Every route registered
+// afterwards through g, or through a Group nested under g (whenever that
+// Group was created), inherits it; routes registered earlier do not.
+func (g *Group) Use(mw ...Middleware) {
+	g.middleware = append(g.middleware, mw...)
+}
+
+// HandleFunc registers path under the Group's prefix and Host constraint,
+// composing handleFunc with the full middleware stack - resolved from the
+// root Router down through every ancestor Group to g - before handing it to
+// the Router.
+func (g *Group) HandleFunc(path string, handleFunc http.HandlerFunc, methods ...string) {
+	fullPath := g.fullPrefix() + path
+	if err := checkMethods(fullPath, methods...); err != nil {
+		registrationPanic(err)
+		return
+	}
+	registrationPanic(g.router.handle(g.fullHost(), fullPath, Use(handleFunc, g.fullMiddleware()...), methods...))
+}
+
+// fullPrefix walks up to the root Group, concatenating every ancestor's
+// prefix with g's own.
+func (g *Group) fullPrefix() string {
+	if g.parent != nil {
+		return g.parent.fullPrefix() + g.prefix
+	}
+	return g.prefix
+}
+
+// fullHost returns g's own Host constraint, or the nearest ancestor's if g
+// didn't set one.
+func (g *Group) fullHost() string {
+	if g.host != "" {
+		return g.host
+	}
+	if g.parent != nil {
+		return g.parent.fullHost()
+	}
+	return ""
+}
+
+// fullMiddleware resolves g's complete middleware stack, read live from the
+// root Router's own stack down through every ancestor Group's, so that a Use
+// call anywhere in the chain is reflected no matter when it runs, as long as
+// it runs before the route is registered.
+func (g *Group) fullMiddleware() []Middleware {
+	var base []Middleware
+	if g.parent != nil {
+		base = g.parent.fullMiddleware()
+	} else {
+		base = g.router.middleware
+	}
+	return append(append([]Middleware{}, base...), g.middleware...)
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func markerMiddleware(name string, trail *[]string) Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			*trail = append(*trail, name)
+			next(w, r)
+		}
+	}
+}
+
+func TestGroupUseAfterGroupCreationStillApplies(t *testing.T) {
+	var trail []string
+	r := &Router{trees: make(map[string]*node), hostExact: make(map[string]*hostBucket)}
+
+	g := r.Group("/api")
+	r.Use(markerMiddleware("auth", &trail))
+	g.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {}, "GET")
+
+	req := httptest.NewRequest("GET", "/api/ping", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(trail) != 1 || trail[0] != "auth" {
+		t.Fatalf("expected Router.Use registered after Group() to still run, got %v", trail)
+	}
+}
+
+func TestNestedGroupComposesFullMiddlewareChain(t *testing.T) {
+	var trail []string
+	r := &Router{trees: make(map[string]*node), hostExact: make(map[string]*hostBucket)}
+
+	r.Use(markerMiddleware("root", &trail))
+	api := r.Group("/api")
+	api.Use(markerMiddleware("api", &trail))
+	v1 := api.Group("/v1")
+	v1.Use(markerMiddleware("v1", &trail))
+	v1.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {}, "GET")
+
+	req := httptest.NewRequest("GET", "/api/v1/ping", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	want := []string{"root", "api", "v1"}
+	if len(trail) != len(want) {
+		t.Fatalf("got trail %v, want %v", trail, want)
+	}
+	for i := range want {
+		if trail[i] != want[i] {
+			t.Fatalf("got trail %v, want %v", trail, want)
+		}
+	}
+}
+
+func TestGroupWithDoesNotMutateParent(t *testing.T) {
+	var trail []string
+	r := &Router{trees: make(map[string]*node), hostExact: make(map[string]*hostBucket)}
+
+	base := r.Group("/api")
+	base.With(markerMiddleware("extra", &trail)).HandleFunc("/only-here", func(w http.ResponseWriter, r *http.Request) {}, "GET")
+	base.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {}, "GET")
+
+	req := httptest.NewRequest("GET", "/api/plain", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(trail) != 0 {
+		t.Fatalf("expected With() middleware to stay scoped to its own routes, got %v", trail)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// hostLabelKind identifies what a single, dot-separated label of a host
+// pattern matches against.
+type hostLabelKind uint8
+
+const (
+	hostLitKind hostLabelKind = iota
+	hostParamKind
+	hostWildcardKind
+)
+
+// hostLabel is one "." separated piece of a parsed host pattern, e.g.
+// "{tenant:[a-z0-9-]+}.example.com" parses into the labels
+// [{tenant, constraint}, example, com].
+type hostLabel struct {
+	kind       hostLabelKind
+	lit        string
+	name       string
+	constraint *constraint
+}
+
+// hostPattern is a fully parsed Router.Host / Group.Host pattern.
+type hostPattern struct {
+	raw    string
+	labels []hostLabel
+}
+
+// exact reports whether pattern has no param or wildcard labels, meaning it
+// can be looked up by a plain map instead of label-by-label matching.
+func (hp *hostPattern) exact() bool {
+	for _, l := range hp.labels {
+		if l.kind != hostLitKind {
+			return false
+		}
+	}
+	return true
+}
+
+// shapeKey returns a representation of hp's label structure where every
+// non-literal label (wildcard or named capture) collapses to the same
+// placeholder, so "*.example.com" and "{tenant}.example.com" compare equal:
+// they match exactly the same set of hosts, and registering both would
+// leave the second permanently shadowed by registration order.
+func (hp *hostPattern) shapeKey() string {
+	parts := make([]string, len(hp.labels))
+	for i, l := range hp.labels {
+		if l.kind == hostLitKind {
+			parts[i] = l.lit
+		} else {
+			parts[i] = "*"
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// parseHostPattern splits pattern on '.' and parses each label as a
+// literal, a "*" single-label wildcard, or a "{name}" / "{name:constraint}"
+// capture.
+func parseHostPattern(pattern string) (*hostPattern, error) {
+	hp := &hostPattern{raw: strings.ToLower(pattern)}
+
+	for _, lbl := range strings.Split(pattern, ".") {
+		switch {
+		case lbl == "*":
+			hp.labels = append(hp.labels, hostLabel{kind: hostWildcardKind})
+		case strings.HasPrefix(lbl, "{") && strings.HasSuffix(lbl, "}"):
+			inner := lbl[1 : len(lbl)-1]
+			name, spec := inner, ""
+			if idx := strings.IndexByte(inner, ':'); idx >= 0 {
+				name, spec = inner[:idx], inner[idx+1:]
+			}
+			if name == "" {
+				return nil, fmt.Errorf("bellt: empty host param name in pattern %q", pattern)
+			}
+			var c *constraint
+			if spec != "" {
+				var err error
+				c, err = parseConstraint(spec)
+				if err != nil {
+					return nil, err
+				}
+			}
+			hp.labels = append(hp.labels, hostLabel{kind: hostParamKind, name: name, constraint: c})
+		default:
+			hp.labels = append(hp.labels, hostLabel{kind: hostLitKind, lit: strings.ToLower(lbl)})
+		}
+	}
+
+	return hp, nil
+}
+
+// match reports whether host (as taken from r.Host, with any port already
+// stripped) satisfies hp, returning the params captured along the way.
+func (hp *hostPattern) match(host string) ([]param, bool) {
+	parts := strings.Split(strings.ToLower(host), ".")
+	if len(parts) != len(hp.labels) {
+		return nil, false
+	}
+
+	var params []param
+	for i, l := range hp.labels {
+		part := parts[i]
+		switch l.kind {
+		case hostLitKind:
+			if part != l.lit {
+				return nil, false
+			}
+		case hostWildcardKind:
+			// matches any single label, nothing captured
+		case hostParamKind:
+			var typed interface{}
+			if l.constraint != nil {
+				v, ok := l.constraint.match(part)
+				if !ok {
+					return nil, false
+				}
+				typed = v
+			}
+			params = append(params, param{name: l.name, value: part, typed: typed})
+		}
+	}
+	return params, true
+}
+
+// stripPort removes a trailing ":port" from a request's Host header. It
+// defers to net.SplitHostPort rather than splitting on the last ':' itself,
+// since a bare IPv6 literal ("[::1]") has no port but still contains
+// colons, and a naive last-colon split would truncate it.
+func stripPort(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+// hostBucket owns the per-method route trees registered under one host
+// pattern. Routes with no host constraint are not kept in a bucket; they
+// live in the Router's own trees.
+type hostBucket struct {
+	pattern *hostPattern
+	trees   map[string]*node
+}
+
+func newHostBucket(pattern *hostPattern) *hostBucket {
+	return &hostBucket{pattern: pattern, trees: make(map[string]*node)}
+}
+
+// bucketForHost returns the bucket that routes registered under the given
+// host pattern should land in, creating and, for wildcard patterns,
+// registration-order-appending it the first time the pattern is seen so
+// that re-registering under the same pattern shares one subtree.
+func (r *Router) bucketForHost(pattern string) (*hostBucket, error) {
+	hp, err := parseHostPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if hp.exact() {
+		if b, ok := r.hostExact[hp.raw]; ok {
+			return b, nil
+		}
+		b := newHostBucket(hp)
+		r.hostExact[hp.raw] = b
+		return b, nil
+	}
+
+	for _, b := range r.hostWild {
+		if b.pattern.raw == hp.raw {
+			return b, nil
+		}
+		if b.pattern.shapeKey() == hp.shapeKey() {
+			return nil, fmt.Errorf(
+				"bellt: host pattern %q conflicts with already registered %q: both match the same set of hosts",
+				pattern, b.pattern.raw)
+		}
+	}
+	b := newHostBucket(hp)
+	r.hostWild = append(r.hostWild, b)
+	return b, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStripPortHandlesBareIPv6Literal(t *testing.T) {
+	if got := stripPort("[::1]"); got != "[::1]" {
+		t.Fatalf("stripPort(%q) = %q, want %q", "[::1]", got, "[::1]")
+	}
+	if got := stripPort("[::1]:8080"); got != "::1" {
+		t.Fatalf("stripPort with port = %q, want %q", got, "::1")
+	}
+	if got := stripPort("example.com:8080"); got != "example.com" {
+		t.Fatalf("stripPort(%q) = %q, want %q", "example.com:8080", got, "example.com")
+	}
+}
+
+func TestHostExactAndWildcardMatch(t *testing.T) {
+	r := NewRouter()
+	r.Host("api.example.com").HandleFunc("/ping", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("exact"))
+	}, "GET")
+
+	var tenant string
+	r.Host("{tenant}.example.com").HandleFunc("/ping", func(w http.ResponseWriter, req *http.Request) {
+		tenant = RouteVariables(req).GetVar("tenant").(string)
+		w.Write([]byte("wild"))
+	}, "GET")
+
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req.Host = "api.example.com"
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Body.String() != "exact" {
+		t.Fatalf("expected the exact host bucket to win for api.example.com, got %q", rec.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/ping", nil)
+	req.Host = "acme.example.com"
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Body.String() != "wild" || tenant != "acme" {
+		t.Fatalf("expected the {tenant} capture to match acme.example.com as %q, got body %q tenant %q",
+			"acme", rec.Body.String(), tenant)
+	}
+}
+
+func TestHostRejectsOverlappingWildcardPatterns(t *testing.T) {
+	r := NewRouter()
+	r.Host("*.example.com").HandleFunc("/ping", func(w http.ResponseWriter, req *http.Request) {}, "GET")
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected registering {tenant}.example.com alongside *.example.com to panic as an ambiguous host conflict")
+		}
+	}()
+	r.Host("{tenant}.example.com").HandleFunc("/ping", func(w http.ResponseWriter, req *http.Request) {}, "GET")
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ctxKey is an unexported type used to store bellt's own values on a
+// request context without colliding with keys set by other packages.
+type ctxKey uint8
+
+const paramsCtxKey ctxKey = 0
+
+// routeParams is the single value bellt stores on a request's context. It
+// holds every param captured while matching the route, avoiding one
+// context.WithValue per param.
+type routeParams []param
+
+// find returns the last param registered under name, mirroring how path
+// segments closer to the leaf shadow ones captured earlier.
+func (p routeParams) find(name string) (param, bool) {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i].name == name {
+			return p[i], true
+		}
+	}
+	return param{}, false
+}
+
+// withRouteParams attaches params to ctx as a single value.
+func withRouteParams(ctx context.Context, params []param) context.Context {
+	return context.WithValue(ctx, paramsCtxKey, routeParams(params))
+}
+
+// ParamReceiver is responsible to return params set on context
+type ParamReceiver struct {
+	request *http.Request
+}
+
+// RouteVariables used to capture and store parameters passed to built routes
+func RouteVariables(r *http.Request) *ParamReceiver {
+	return &ParamReceiver{request: r}
+}
+
+// GetVar return a value of router variable. For a param matched through a
+// typed constraint (int, uuid, a time layout, ...) it returns the coerced
+// value; otherwise it returns the raw string.
+func (pr *ParamReceiver) GetVar(variable string) interface{} {
+	p, ok := pr.params().find(variable)
+	if !ok {
+		return nil
+	}
+	if p.typed != nil {
+		return p.typed
+	}
+	return p.value
+}
+
+// GetInt returns the param coerced through an {name:int} constraint, or 0
+// if variable was not captured as an int.
+func (pr *ParamReceiver) GetInt(variable string) int {
+	v, _ := pr.GetVar(variable).(int)
+	return v
+}
+
+// GetUUID returns the param coerced through an {name:uuid} constraint, or
+// the empty UUID if variable was not captured as one. UUID is bellt's own
+// shape-validated string type, not github.com/google/uuid.UUID - see the
+// UUID doc comment for what that does and doesn't check.
+func (pr *ParamReceiver) GetUUID(variable string) UUID {
+	v, _ := pr.GetVar(variable).(UUID)
+	return v
+}
+
+// GetTime returns the param coerced through a time-layout constraint (e.g.
+// {name:2006-01-02}), or the zero time if variable was not captured as one.
+func (pr *ParamReceiver) GetTime(variable string) time.Time {
+	v, _ := pr.GetVar(variable).(time.Time)
+	return v
+}
+
+func (pr *ParamReceiver) params() routeParams {
+	params, _ := pr.request.Context().Value(paramsCtxKey).(routeParams)
+	return params
+}
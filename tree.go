@@ -0,0 +1,307 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// segKind identifies what a piece of a registered pattern represents once
+// the pattern has been split apart by parsePattern.
+type segKind uint8
+
+const (
+	segLiteral segKind = iota
+	segParam
+	segCatchAll
+)
+
+// segment is a single piece of a parsed pattern: either a literal run of
+// characters or a named parameter (`{name}` / `{name:constraint}`) /
+// catch-all (`*name`) slot.
+type segment struct {
+	kind       segKind
+	lit        string
+	name       string
+	constraint string // raw text after ':' in "{name:constraint}", empty if none
+}
+
+// nodeKind identifies the role a tree node plays when matching a path.
+type nodeKind uint8
+
+const (
+	nodeStatic nodeKind = iota
+	nodeParam
+	nodeCatchAll
+)
+
+// endpoint is the handler registered for a given method on a fully built
+// route, together with the pattern it was registered under, which insert
+// compares to detect a conflicting re-registration.
+type endpoint struct {
+	handler http.HandlerFunc
+	pattern string
+}
+
+// node is a single vertex of the per-method radix tree. Static children are
+// stored by their first byte so a lookup can jump straight to the matching
+// branch instead of scanning every sibling, while a node may additionally
+// carry any number of param children (at most one of them unconstrained)
+// and one catch-all child.
+type node struct {
+	prefix     string
+	kind       nodeKind
+	name       string      // param / catch-all name, unused for static nodes
+	constraint *constraint // param constraint, nil for an unconstrained param
+	static     map[byte]*node
+	params     []*node // constrained params tried first, then the unconstrained one, if any
+	catchAll   *node
+	endpoint   *endpoint
+}
+
+func newNode(kind nodeKind) *node {
+	return &node{kind: kind, static: make(map[byte]*node)}
+}
+
+// findParam returns the existing param child whose constraint spec matches
+// c (comparing the unconstrained case as its own bucket), so that
+// registering the same "{name:constraint}" twice reuses one subtree instead
+// of creating an ambiguous sibling.
+func (n *node) findParam(c *constraint) *node {
+	spec := ""
+	if c != nil {
+		spec = c.spec
+	}
+	for _, p := range n.params {
+		pspec := ""
+		if p.constraint != nil {
+			pspec = p.constraint.spec
+		}
+		if pspec == spec {
+			return p
+		}
+	}
+	return nil
+}
+
+// parsePattern splits a registered path such as "/users/{id}/posts/*rest"
+// into literal and param/catch-all segments.
+func parsePattern(path string) ([]segment, error) {
+	var segs []segment
+	var lit strings.Builder
+
+	flush := func() {
+		if lit.Len() > 0 {
+			segs = append(segs, segment{kind: segLiteral, lit: lit.String()})
+			lit.Reset()
+		}
+	}
+
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '{':
+			end := strings.IndexByte(path[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("bellt: unclosed '{' in pattern %q", path)
+			}
+			name := path[i+1 : i+end]
+			var constr string
+			if idx := strings.IndexByte(name, ':'); idx >= 0 {
+				name, constr = name[:idx], name[idx+1:]
+			}
+			if name == "" {
+				return nil, fmt.Errorf("bellt: empty param name in pattern %q", path)
+			}
+			flush()
+			segs = append(segs, segment{kind: segParam, name: name, constraint: constr})
+			i += end
+		case '*':
+			flush()
+			name := path[i+1:]
+			if name == "" {
+				name = "*"
+			}
+			segs = append(segs, segment{kind: segCatchAll, name: name})
+			i = len(path)
+		default:
+			lit.WriteByte(c)
+		}
+	}
+	flush()
+	return segs, nil
+}
+
+// insert registers handler for method against the pattern represented by
+// segs, splitting and creating nodes as needed and detecting routes that
+// conflict with ones already registered.
+func (n *node) insert(segs []segment, method, pattern string, handler http.HandlerFunc) error {
+	if len(segs) == 0 {
+		if n.endpoint != nil && n.endpoint.pattern != pattern {
+			return fmt.Errorf("bellt: route %q conflicts with already registered %q",
+				pattern, n.endpoint.pattern)
+		}
+		n.endpoint = &endpoint{handler: handler, pattern: pattern}
+		return nil
+	}
+
+	switch segs[0].kind {
+	case segParam:
+		var c *constraint
+		if segs[0].constraint != "" {
+			var err error
+			c, err = parseConstraint(segs[0].constraint)
+			if err != nil {
+				return err
+			}
+		}
+
+		child := n.findParam(c)
+		if child == nil {
+			child = newNode(nodeParam)
+			child.name = segs[0].name
+			child.constraint = c
+			if c != nil {
+				n.params = append([]*node{child}, n.params...)
+			} else {
+				n.params = append(n.params, child)
+			}
+		} else if child.name != segs[0].name {
+			return fmt.Errorf("bellt: param %q conflicts with already registered {%s} at the same position",
+				segs[0].name, child.name)
+		}
+		return child.insert(segs[1:], method, pattern, handler)
+	case segCatchAll:
+		if n.catchAll == nil {
+			n.catchAll = newNode(nodeCatchAll)
+			n.catchAll.name = segs[0].name
+		} else if n.catchAll.name != segs[0].name {
+			return fmt.Errorf("bellt: catch-all *%s conflicts with already registered *%s",
+				segs[0].name, n.catchAll.name)
+		}
+		return n.catchAll.insert(segs[1:], method, pattern, handler)
+	default:
+		return n.insertLiteral(segs[0].lit, segs[1:], method, pattern, handler)
+	}
+}
+
+// insertLiteral walks (and splits, on the longest common prefix) the
+// character-level static branch of the tree before handing control back to
+// insert for the remaining param/catch-all segments.
+func (n *node) insertLiteral(lit string, rest []segment, method, pattern string, handler http.HandlerFunc) error {
+	if lit == "" {
+		return n.insert(rest, method, pattern, handler)
+	}
+
+	child, ok := n.static[lit[0]]
+	if !ok {
+		leaf := newNode(nodeStatic)
+		leaf.prefix = lit
+		n.static[lit[0]] = leaf
+		return leaf.insert(rest, method, pattern, handler)
+	}
+
+	common := commonPrefixLen(child.prefix, lit)
+
+	if common < len(child.prefix) {
+		split := newNode(nodeStatic)
+		split.prefix = child.prefix[common:]
+		split.static = child.static
+		split.params = child.params
+		split.catchAll = child.catchAll
+		split.endpoint = child.endpoint
+
+		child.prefix = child.prefix[:common]
+		child.static = map[byte]*node{split.prefix[0]: split}
+		child.params = nil
+		child.catchAll = nil
+		child.endpoint = nil
+	}
+
+	return child.insertLiteral(lit[common:], rest, method, pattern, handler)
+}
+
+func commonPrefixLen(a, b string) int {
+	max := len(a)
+	if len(b) < max {
+		max = len(b)
+	}
+	i := 0
+	for i < max && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// param is a single captured name/value pair yielded by a lookup. typed
+// holds the constraint-coerced value (int, UUID, time.Time, ...) when the
+// matching param carried a typed constraint, nil otherwise.
+type param struct {
+	name  string
+	value string
+	typed interface{}
+}
+
+// lookup walks the tree matching path against it, preferring static
+// branches over param branches over catch-all branches, and backtracking
+// whenever a more specific branch fails to reach a registered endpoint.
+func (n *node) lookup(path string, params []param) (*endpoint, []param) {
+	if len(n.prefix) > 0 {
+		if !strings.HasPrefix(path, n.prefix) {
+			return nil, nil
+		}
+		path = path[len(n.prefix):]
+	}
+
+	if path == "" {
+		if n.endpoint != nil {
+			return n.endpoint, params
+		}
+		return n.tryParamOrCatchAll("", params)
+	}
+
+	if child, ok := n.static[path[0]]; ok {
+		if ep, p := child.lookup(path, params); ep != nil {
+			return ep, p
+		}
+	}
+
+	return n.tryParamOrCatchAll(path, params)
+}
+
+func (n *node) tryParamOrCatchAll(path string, params []param) (*endpoint, []param) {
+	seg := path
+	if idx := strings.IndexByte(path, '/'); idx >= 0 {
+		seg = path[:idx]
+	}
+
+	if seg != "" {
+		rest := path[len(seg):]
+		for _, pn := range n.params {
+			var typed interface{}
+			if pn.constraint != nil {
+				v, ok := pn.constraint.match(seg)
+				if !ok {
+					continue
+				}
+				typed = v
+			}
+			next := append(append([]param{}, params...), param{name: pn.name, value: seg, typed: typed})
+			if ep, p := pn.lookup(rest, next); ep != nil {
+				return ep, p
+			}
+		}
+	}
+
+	if n.catchAll != nil {
+		next := append(append([]param{}, params...), param{name: n.catchAll.name, value: path})
+		if n.catchAll.endpoint != nil {
+			return n.catchAll.endpoint, next
+		}
+	}
+
+	return nil, nil
+}
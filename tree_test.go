@@ -0,0 +1,112 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import (
+	"net/http"
+	"testing"
+)
+
+func mustInsert(t *testing.T, n *node, pattern string) {
+	t.Helper()
+	segs, err := parsePattern(pattern)
+	if err != nil {
+		t.Fatalf("parsePattern(%q): %v", pattern, err)
+	}
+	if err := n.insert(segs, "GET", pattern, func(http.ResponseWriter, *http.Request) {}); err != nil {
+		t.Fatalf("insert(%q): %v", pattern, err)
+	}
+}
+
+func TestTreeLookupStaticBeatsParam(t *testing.T) {
+	root := newNode(nodeStatic)
+	mustInsert(t, root, "/users/me")
+	mustInsert(t, root, "/users/{id}")
+
+	ep, params := root.lookup("/users/me", nil)
+	if ep == nil || ep.pattern != "/users/me" {
+		t.Fatalf("expected the static route to win, got %+v", ep)
+	}
+	if len(params) != 0 {
+		t.Fatalf("expected no params for the static match, got %v", params)
+	}
+
+	ep, params = root.lookup("/users/42", nil)
+	if ep == nil || ep.pattern != "/users/{id}" {
+		t.Fatalf("expected the param route, got %+v", ep)
+	}
+	if len(params) != 1 || params[0].name != "id" || params[0].value != "42" {
+		t.Fatalf("unexpected params: %v", params)
+	}
+}
+
+func TestTreeLookupBacktracksToCatchAll(t *testing.T) {
+	root := newNode(nodeStatic)
+	mustInsert(t, root, "/files/{name}")
+	mustInsert(t, root, "/files/*rest")
+
+	// "/files/a/b" has too many segments for {name}, which only ever
+	// matches one - the catch-all must still be tried.
+	ep, params := root.lookup("/files/a/b", nil)
+	if ep == nil || ep.pattern != "/files/*rest" {
+		t.Fatalf("expected the catch-all route, got %+v", ep)
+	}
+	if len(params) != 1 || params[0].name != "rest" || params[0].value != "a/b" {
+		t.Fatalf("unexpected params: %v", params)
+	}
+}
+
+func TestTreeLookupCatchAllMatchesEmptyRemainder(t *testing.T) {
+	root := newNode(nodeStatic)
+	mustInsert(t, root, "/files/*rest")
+
+	// "/files/" has nothing left after the literal prefix is consumed -
+	// httprouter-style catch-alls still match here, with rest="".
+	ep, params := root.lookup("/files/", nil)
+	if ep == nil || ep.pattern != "/files/*rest" {
+		t.Fatalf("expected the catch-all route to match an empty remainder, got %+v", ep)
+	}
+	if len(params) != 1 || params[0].name != "rest" || params[0].value != "" {
+		t.Fatalf("expected rest=\"\", got %v", params)
+	}
+}
+
+func TestTreeInsertSplitsOnCommonPrefix(t *testing.T) {
+	root := newNode(nodeStatic)
+	mustInsert(t, root, "/team")
+	mustInsert(t, root, "/teams")
+
+	if ep, _ := root.lookup("/team", nil); ep == nil || ep.pattern != "/team" {
+		t.Fatalf("expected /team to still match after splitting, got %+v", ep)
+	}
+	if ep, _ := root.lookup("/teams", nil); ep == nil || ep.pattern != "/teams" {
+		t.Fatalf("expected /teams to match, got %+v", ep)
+	}
+	if ep, _ := root.lookup("/tea", nil); ep != nil {
+		t.Fatalf("expected no match for /tea, got %+v", ep)
+	}
+}
+
+func TestTreeInsertDetectsParamNameConflict(t *testing.T) {
+	root := newNode(nodeStatic)
+	mustInsert(t, root, "/users/{id}")
+
+	segs, _ := parsePattern("/users/{name}")
+	err := root.insert(segs, "GET", "/users/{name}", func(http.ResponseWriter, *http.Request) {})
+	if err == nil {
+		t.Fatal("expected a conflict error registering {name} alongside {id} at the same position")
+	}
+}
+
+func TestTreeInsertDetectsCatchAllNameConflict(t *testing.T) {
+	root := newNode(nodeStatic)
+	mustInsert(t, root, "/files/*rest")
+
+	segs, _ := parsePattern("/files/*path")
+	err := root.insert(segs, "GET", "/files/*path", func(http.ResponseWriter, *http.Request) {})
+	if err == nil {
+		t.Fatal("expected a conflict error registering *path alongside *rest at the same position")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 Guilherme Caruso. All rights reserved.
+// Use of this source code is governed by a MIT License
+// license that can be found in the LICENSE file.
+
+package bellt
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// validateStruct walks v's fields looking for `validate` struct tags and
+// returns one human-readable message per failed rule. bellt implements a
+// small, dependency-free subset of go-playground/validator's tag
+// vocabulary - "required", "min=N" and "max=N" (string length for strings,
+// numeric bounds for numbers). Any other rule name - "email", "oneof=...",
+// "dive", and the rest of that package's surface - is not recognized and
+// is treated as a no-op rather than a failure or a construction-time
+// panic: a struct tagged for a rule bellt doesn't enforce should still
+// boot and still enforce the rules it does understand, not refuse to
+// start over the tags it doesn't.
+func validateStruct(v interface{}) []string {
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+
+	var errs []string
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		for _, rule := range strings.Split(tag, ",") {
+			if err := applyRule(field.Name, rv.Field(i), rule); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	return errs
+}
+
+func applyRule(fieldName string, fv reflect.Value, rule string) error {
+	name, arg, _ := strings.Cut(rule, "=")
+
+	switch name {
+	case "required":
+		if fv.IsZero() {
+			return fmt.Errorf("%s is required", fieldName)
+		}
+	case "min":
+		return checkBound(fieldName, fv, arg, false)
+	case "max":
+		return checkBound(fieldName, fv, arg, true)
+	}
+	return nil
+}
+
+func checkBound(fieldName string, fv reflect.Value, arg string, isMax bool) error {
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return nil
+	}
+
+	var value float64
+	switch fv.Kind() {
+	case reflect.String:
+		value = float64(len(fv.String()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = float64(fv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = float64(fv.Uint())
+	case reflect.Float32, reflect.Float64:
+		value = fv.Float()
+	case reflect.Slice, reflect.Array, reflect.Map:
+		value = float64(fv.Len())
+	default:
+		return nil
+	}
+
+	if isMax && value > bound {
+		return fmt.Errorf("%s must be at most %s", fieldName, arg)
+	}
+	if !isMax && value < bound {
+		return fmt.Errorf("%s must be at least %s", fieldName, arg)
+	}
+	return nil
+}